@@ -0,0 +1,335 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/vitovt/OcImage2Downloader/pkg/downloader"
+)
+
+func main() {
+	myApp := app.New()
+	myWindow := myApp.NewWindow("Google Spreadsheet Image Downloader")
+
+	// Source type selection
+	sourceTypeOptions := []string{"Google Sheet", "Local File", "HTTP(S) URL"}
+	sourceTypeLabel := widget.NewLabel("Data Source:")
+
+	// Input fields with labels
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("Enter Google Spreadsheet URL")
+	urlLabel := widget.NewLabel("Spreadsheet URL:")
+
+	filePathEntry := widget.NewEntry()
+	filePathEntry.SetPlaceHolder("Path to local .csv, .tsv, or .xlsx file")
+	browseButton := widget.NewButton("Browse...", nil)
+	filePathRow := container.NewBorder(nil, nil, nil, browseButton, filePathEntry)
+	filePathLabel := widget.NewLabel("Local File:")
+
+	basicUserEntry := widget.NewEntry()
+	basicUserEntry.SetPlaceHolder("Basic auth username (optional)")
+	basicPassEntry := widget.NewPasswordEntry()
+	basicPassEntry.SetPlaceHolder("Basic auth password (optional)")
+	bearerTokenEntry := widget.NewEntry()
+	bearerTokenEntry.SetPlaceHolder("Bearer token (optional)")
+	authLabel := widget.NewLabel("HTTP Auth:")
+	authRow := container.NewGridWithColumns(3, basicUserEntry, basicPassEntry, bearerTokenEntry)
+
+	sourceTypeEntry := widget.NewSelect(sourceTypeOptions, func(value string) {
+		filePathLabel.Hide()
+		filePathRow.Hide()
+		urlLabel.Hide()
+		urlEntry.Hide()
+		authLabel.Hide()
+		authRow.Hide()
+		switch value {
+		case "Local File":
+			filePathLabel.Show()
+			filePathRow.Show()
+		case "HTTP(S) URL":
+			urlLabel.Show()
+			urlEntry.Show()
+			authLabel.Show()
+			authRow.Show()
+		default: // Google Sheet
+			urlLabel.Show()
+			urlEntry.Show()
+		}
+	})
+	sourceTypeEntry.SetSelected("Google Sheet")
+
+	browseButton.OnTapped = func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			filePathEntry.SetText(reader.URI().Path())
+		}, myWindow)
+	}
+
+	columnsEntry := widget.NewEntry()
+	columnsEntry.SetText("body_uk,body_ru")
+	columnsLabel := widget.NewLabel("Content Columns:")
+
+	hostnameEntry := widget.NewEntry()
+	hostnameEntry.SetPlaceHolder("Hostname (e.g., https://site.com.ua)")
+	hostnameEntry.SetText("https://site.com.ua") // Default value
+	hostnameLabel := widget.NewLabel("Images Default Hostname:")
+
+	imagedirEntry := widget.NewEntry()
+	imagedirEntry.SetPlaceHolder("Image Directory (e.g., /content/uploads/images/)")
+	imagedirEntry.SetText("/content/uploads/images/") // Default value
+	imagedirLabel := widget.NewLabel("Directory to Download Files:")
+
+	outputFileEntry := widget.NewEntry()
+	outputFileEntry.SetPlaceHolder("Output CSV File Name (e.g., output.csv)")
+	outputFileEntry.SetText("output.csv") // Default value
+	outputFileLabel := widget.NewLabel("File with Updated Descriptions:")
+
+	// Separator selection
+	separatorOptions := []string{"Comma (,)", "Semicolon (;)", "Tab (\\t)"}
+	separatorEntry := widget.NewSelect(separatorOptions, func(value string) {
+		// Handle selection change if needed
+	})
+	separatorEntry.SetSelected("Semicolon (;)") // Default value
+	separatorLabel := widget.NewLabel("CSV Separator:")
+
+	concurrencyEntry := widget.NewEntry()
+	concurrencyEntry.SetText(fmt.Sprintf("%d", downloader.DefaultConcurrency))
+	concurrencyLabel := widget.NewLabel("Parallel Downloads:")
+
+	// Image post-processing pipeline
+	dedupCheck := widget.NewCheck("Deduplicate identical images", nil)
+	processingLabel := widget.NewLabel("Image Processing:")
+
+	reencodeOptions := []string{"None", "webp"}
+	reencodeEntry := widget.NewSelect(reencodeOptions, nil)
+	reencodeEntry.SetSelected("None")
+	reencodeLabel := widget.NewLabel("Re-encode Format:")
+
+	qualityEntry := widget.NewEntry()
+	qualityEntry.SetText("80")
+	qualityLabel := widget.NewLabel("Re-encode Quality (1-100):")
+
+	widthsEntry := widget.NewEntry()
+	widthsEntry.SetPlaceHolder("e.g. 320,640,1024")
+	widthsLabel := widget.NewLabel("Responsive Widths:")
+
+	dryRunCheck := widget.NewCheck("Dry run (report only, write nothing)", nil)
+
+	// Status label and multi-bar progress view: one bar for overall
+	// progress plus one per download worker, replaced each run to match
+	// the configured concurrency.
+	statusBinding := binding.NewString()
+	statusLabel := widget.NewLabelWithData(statusBinding)
+	progressHolder := container.NewStack(newMultiBarProgress(downloader.DefaultConcurrency).View())
+
+	// Initialize bindings
+	statusBinding.Set("Status: Idle")
+
+	// Process Button
+	processButton := widget.NewButton("Process Images", func() {
+		spreadsheetURL := urlEntry.Text
+		hostname := hostnameEntry.Text
+		imagedir := imagedirEntry.Text
+		outputFileName := outputFileEntry.Text
+		selectedSeparator := separatorEntry.Selected
+		concurrency, err := strconv.Atoi(concurrencyEntry.Text)
+		if err != nil || concurrency <= 0 {
+			concurrency = downloader.DefaultConcurrency
+		}
+		columns := downloader.ParseColumns(columnsEntry.Text)
+
+		reencodeFormat := reencodeEntry.Selected
+		if reencodeFormat == "None" {
+			reencodeFormat = ""
+		}
+		quality, err := strconv.Atoi(qualityEntry.Text)
+		if err != nil || quality <= 0 {
+			quality = 80
+		}
+		procOpts := downloader.ProcessOptions{
+			Dedup:            dedupCheck.Checked,
+			ReencodeFormat:   reencodeFormat,
+			ReencodeQuality:  quality,
+			ResponsiveWidths: downloader.ParseWidths(widthsEntry.Text),
+			DryRun:           dryRunCheck.Checked,
+		}
+
+		// Collect missing fields
+		var missingFields []string
+		switch sourceTypeEntry.Selected {
+		case "Local File":
+			if filePathEntry.Text == "" {
+				missingFields = append(missingFields, "Local File")
+			}
+		default:
+			if spreadsheetURL == "" {
+				missingFields = append(missingFields, "Spreadsheet URL")
+			}
+		}
+		if hostname == "" {
+			missingFields = append(missingFields, "Hostname")
+		}
+		if imagedir == "" {
+			missingFields = append(missingFields, "Image Directory")
+		}
+		if outputFileName == "" {
+			missingFields = append(missingFields, "Output CSV File Name")
+		}
+		if len(columns) == 0 {
+			missingFields = append(missingFields, "Content Columns")
+		}
+
+		// Show detailed error message if any fields are missing
+		if len(missingFields) > 0 {
+			showError(myWindow, errors.New("Please fill in the following fields: "+strings.Join(missingFields, ", ")))
+			return
+		}
+
+		source := downloader.BuildInputSource(sourceTypeEntry.Selected, spreadsheetURL, filePathEntry.Text, downloader.SeparatorRune(selectedSeparator), basicUserEntry.Text, basicPassEntry.Text, bearerTokenEntry.Text)
+
+		bars := newMultiBarProgress(concurrency)
+		progressHolder.Objects = []fyne.CanvasObject{bars.View()}
+		progressHolder.Refresh()
+		reporter := &guiReporter{bars: bars, status: statusBinding}
+
+		// Function to start processing
+		startProcessing := func() {
+			go func() {
+				updateStatus(statusBinding, "Fetching source data...")
+				records, err := source.Fetch()
+				if err != nil {
+					showError(myWindow, err)
+					updateStatus(statusBinding, "Status: Idle")
+					return
+				}
+
+				updateStatus(statusBinding, "Processing records...")
+				failures, err := downloader.ProcessRecords(records, hostname, imagedir, outputFileName, selectedSeparator, concurrency, columns, procOpts, downloader.DefaultRetryPolicy, reporter)
+				if err != nil {
+					showError(myWindow, err)
+					updateStatus(statusBinding, "Status: Idle")
+					return
+				}
+
+				updateStatus(statusBinding, "Status: Completed")
+				message := "Images downloaded and data processed successfully.\nOutput saved to " + outputFileName
+				if failures > 0 {
+					message = fmt.Sprintf("%s\n%d image(s) failed; see the report for details.", message, failures)
+				}
+				showInfo(myWindow, message)
+			}()
+		}
+
+		// Function to check output file and proceed
+		checkOutputFileAndProcess := func() {
+			if downloader.FileExists(outputFileName) {
+				dialog.ShowConfirm("File Exists",
+					fmt.Sprintf("The output file '%s' already exists. Do you want to delete it and proceed?", outputFileName),
+					func(confirmed bool) {
+						if confirmed {
+							err := os.Remove(outputFileName)
+							if err != nil {
+								showError(myWindow, fmt.Errorf("Failed to delete file '%s': %v", outputFileName, err))
+								return
+							}
+							startProcessing()
+						} else {
+							updateStatus(statusBinding, "Operation Aborted")
+							showError(myWindow, fmt.Errorf("File '%s' exists, aborting...", outputFileName))
+							return
+						}
+					}, myWindow)
+			} else {
+				startProcessing()
+			}
+		}
+
+		// Check if image directory exists
+		imageDirPath := filepath.Join("files", imagedir)
+		if downloader.DirExists(imageDirPath) {
+			dialog.ShowConfirm("Directory Exists",
+				fmt.Sprintf("The directory '%s' already exists. Do you want to delete it and proceed?", imageDirPath),
+				func(confirmed bool) {
+					if confirmed {
+						err := os.RemoveAll(imageDirPath)
+						if err != nil {
+							showError(myWindow, fmt.Errorf("Failed to delete directory '%s': %v", imageDirPath, err))
+							return
+						}
+						checkOutputFileAndProcess()
+					} else {
+						updateStatus(statusBinding, "Operation Aborted")
+						showError(myWindow, fmt.Errorf("Directory '%s' exists, aborting...", imageDirPath))
+						return
+					}
+				}, myWindow)
+		} else {
+			checkOutputFileAndProcess()
+		}
+	})
+
+	// Organize UI Elements
+	mainbox := container.New(
+		layout.NewFormLayout(),
+		sourceTypeLabel, sourceTypeEntry,
+		urlLabel, urlEntry,
+		filePathLabel, filePathRow,
+		authLabel, authRow,
+		columnsLabel, columnsEntry,
+		hostnameLabel, hostnameEntry,
+		imagedirLabel, imagedirEntry,
+		outputFileLabel, outputFileEntry,
+		separatorLabel, separatorEntry,
+		concurrencyLabel, concurrencyEntry,
+		processingLabel, dedupCheck,
+		reencodeLabel, reencodeEntry,
+		qualityLabel, qualityEntry,
+		widthsLabel, widthsEntry,
+		widget.NewLabel(""), dryRunCheck,
+	)
+	filePathLabel.Hide()
+	filePathRow.Hide()
+	authLabel.Hide()
+	authRow.Hide()
+
+	content := container.NewVBox(
+		mainbox,
+		processButton,
+		progressHolder,
+		statusLabel,
+	)
+
+	myWindow.SetContent(content)
+	myWindow.Resize(fyne.NewSize(800, 600))
+	myWindow.ShowAndRun()
+}
+
+// showError displays an error dialog
+func showError(win fyne.Window, err error) {
+	dialog.ShowError(err, win)
+}
+
+// showInfo displays an information dialog
+func showInfo(win fyne.Window, message string) {
+	dialog.ShowInformation("Success", message, win)
+}
+
+// updateStatus updates the status binding
+func updateStatus(statusBinding binding.String, status string) {
+	statusBinding.Set("Status: " + status)
+}
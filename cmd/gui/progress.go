@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/vitovt/OcImage2Downloader/pkg/downloader"
+)
+
+// WorkerProgress drives one row of the multi-bar download view: a progress
+// fraction plus a status line showing the file currently being
+// transferred, its bytes/total, and the transfer speed.
+type WorkerProgress struct {
+	fraction binding.Float
+	status   binding.String
+}
+
+func newWorkerProgress() *WorkerProgress {
+	w := &WorkerProgress{fraction: binding.NewFloat(), status: binding.NewString()}
+	w.idle()
+	return w
+}
+
+// set updates this row to reflect transferred/total bytes of filename,
+// downloaded at the given rate in bytes per second.
+func (w *WorkerProgress) set(filename string, transferred, total int64, bytesPerSecond float64) {
+	if total > 0 {
+		w.fraction.Set(float64(transferred) / float64(total))
+	}
+	w.status.Set(fmt.Sprintf("%s — %s/%s (%s/s)", filename, downloader.FormatBytes(transferred), downloader.FormatBytes(total), downloader.FormatBytes(int64(bytesPerSecond))))
+}
+
+// idle resets this row to its at-rest state between downloads.
+func (w *WorkerProgress) idle() {
+	w.fraction.Set(0)
+	w.status.Set("idle")
+}
+
+// MultiBarProgress replaces the single overall progress bar with a
+// scrollable list of per-task bars: one for overall progress across the
+// whole batch, and one per concurrent download worker.
+type MultiBarProgress struct {
+	Overall *WorkerProgress
+	Workers []*WorkerProgress
+	view    fyne.CanvasObject
+}
+
+// newMultiBarProgress builds a MultiBarProgress with one row per worker,
+// sized for workerCount concurrent downloads.
+func newMultiBarProgress(workerCount int) *MultiBarProgress {
+	m := &MultiBarProgress{Overall: newWorkerProgress()}
+
+	rows := container.NewVBox(progressRow("Overall", m.Overall))
+	m.Workers = make([]*WorkerProgress, workerCount)
+	for i := range m.Workers {
+		m.Workers[i] = newWorkerProgress()
+		rows.Add(progressRow(fmt.Sprintf("Worker %d", i+1), m.Workers[i]))
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(0, 160))
+	m.view = scroll
+	return m
+}
+
+// View returns the widget tree to embed in the main window.
+func (m *MultiBarProgress) View() fyne.CanvasObject {
+	return m.view
+}
+
+// progressRow lays out one labeled progress bar and its status line.
+func progressRow(label string, w *WorkerProgress) fyne.CanvasObject {
+	return container.NewBorder(nil, nil, widget.NewLabel(label), nil,
+		container.NewVBox(
+			widget.NewProgressBarWithData(w.fraction),
+			widget.NewLabelWithData(w.status),
+		),
+	)
+}
+
+// guiReporter adapts a MultiBarProgress and a status binding to
+// downloader.ProgressReporter, so ProcessRecords can drive the GUI without
+// depending on Fyne itself.
+type guiReporter struct {
+	bars   *MultiBarProgress
+	status binding.String
+}
+
+func (r *guiReporter) SetOverall(fraction float64) {
+	r.bars.Overall.fraction.Set(fraction)
+}
+
+func (r *guiReporter) SetWorker(index int, filename string, transferred, total int64, bytesPerSecond float64) {
+	r.bars.Workers[index].set(filename, transferred, total, bytesPerSecond)
+}
+
+func (r *guiReporter) WorkerIdle(index int) {
+	r.bars.Workers[index].idle()
+}
+
+func (r *guiReporter) Status(message string) {
+	updateStatus(r.status, message)
+}
@@ -0,0 +1,261 @@
+// Command ocimg is a headless, scriptable front end for the downloader
+// engine: the same spreadsheet-to-local-images pipeline as the GUI, driven
+// entirely by flags (or a --config file), with progress written to stderr
+// so stdout stays free for other tooling and the run can be cron'd or
+// chained across many spreadsheets without a display server.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vitovt/OcImage2Downloader/pkg/downloader"
+)
+
+// config mirrors the CLI flags. It can be loaded from a YAML file via
+// --config to avoid repeating a long flag list across many invocations;
+// any flag given explicitly on the command line overrides the
+// corresponding config file value.
+type config struct {
+	Source      string `yaml:"source"`
+	URL         string `yaml:"url"`
+	File        string `yaml:"file"`
+	BasicUser   string `yaml:"basic_user"`
+	BasicPass   string `yaml:"basic_pass"`
+	BearerToken string `yaml:"bearer_token"`
+	Hostname    string `yaml:"hostname"`
+	ImageDir    string `yaml:"imagedir"`
+	Output      string `yaml:"output"`
+	Separator   string `yaml:"separator"`
+	Columns     string `yaml:"columns"`
+	Concurrency int    `yaml:"concurrency"`
+	MaxRetries  int    `yaml:"max_retries"`
+	RetryBaseMS int    `yaml:"retry_base_ms"`
+	RetryMaxMS  int    `yaml:"retry_max_ms"`
+	Dedup       bool   `yaml:"dedup"`
+	Reencode    string `yaml:"reencode"`
+	Quality     int    `yaml:"quality"`
+	Widths      string `yaml:"widths"`
+	DryRun      bool   `yaml:"dry_run"`
+	Verbose     bool   `yaml:"verbose"`
+}
+
+func defaultConfig() config {
+	return config{
+		Source:      "google",
+		Separator:   "Semicolon (;)",
+		Columns:     "body_uk,body_ru",
+		Concurrency: downloader.DefaultConcurrency,
+		MaxRetries:  downloader.DefaultRetryPolicy.MaxRetries,
+		RetryBaseMS: int(downloader.DefaultRetryPolicy.BaseDelay.Milliseconds()),
+		RetryMaxMS:  int(downloader.DefaultRetryPolicy.MaxDelay.Milliseconds()),
+		Quality:     80,
+	}
+}
+
+// sourceTypeLabel maps the CLI's short --source values to the labels
+// downloader.BuildInputSource expects.
+func sourceTypeLabel(source string) string {
+	switch strings.ToLower(source) {
+	case "local":
+		return "Local File"
+	case "http":
+		return "HTTP(S) URL"
+	default:
+		return "Google Sheet"
+	}
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "ocimg: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	cfg := defaultConfig()
+
+	fs := flag.NewFlagSet("ocimg", flag.ContinueOnError)
+	var configPath string
+	var flagURL, flagFile, flagBasicUser, flagBasicPass, flagBearerToken string
+	var flagSource, flagHostname, flagImageDir, flagOutput, flagSeparator, flagColumns string
+	var flagReencode, flagWidths string
+	var flagConcurrency, flagMaxRetries, flagRetryBaseMS, flagRetryMaxMS, flagQuality int
+	var flagDedup, flagDryRun, flagVerbose bool
+
+	fs.StringVar(&configPath, "config", "", "path to a YAML config file providing any of the flags below")
+	fs.StringVar(&flagSource, "source", cfg.Source, `input source: "google", "local", or "http"`)
+	fs.StringVar(&flagURL, "url", cfg.URL, "Google Sheet or HTTP(S) URL to fetch")
+	fs.StringVar(&flagFile, "file", cfg.File, "local .csv, .tsv, or .xlsx file to read")
+	fs.StringVar(&flagBasicUser, "basic-user", cfg.BasicUser, "HTTP Basic Auth username (source=http)")
+	fs.StringVar(&flagBasicPass, "basic-pass", cfg.BasicPass, "HTTP Basic Auth password (source=http)")
+	fs.StringVar(&flagBearerToken, "bearer-token", cfg.BearerToken, "HTTP bearer token (source=http)")
+	fs.StringVar(&flagHostname, "hostname", cfg.Hostname, "default hostname for relative image URLs")
+	fs.StringVar(&flagImageDir, "imagedir", cfg.ImageDir, "directory (under files/) to save downloaded images")
+	fs.StringVar(&flagOutput, "output", cfg.Output, "output CSV file name")
+	fs.StringVar(&flagSeparator, "separator", cfg.Separator, `CSV separator: "Comma (,)", "Semicolon (;)", or "Tab (\t)"`)
+	fs.StringVar(&flagColumns, "columns", cfg.Columns, "comma-separated content column names to scan for images")
+	fs.IntVar(&flagConcurrency, "concurrency", cfg.Concurrency, "number of concurrent downloads")
+	fs.IntVar(&flagMaxRetries, "max-retries", cfg.MaxRetries, "maximum download attempts per image")
+	fs.IntVar(&flagRetryBaseMS, "retry-base-ms", cfg.RetryBaseMS, "base retry backoff in milliseconds")
+	fs.IntVar(&flagRetryMaxMS, "retry-max-ms", cfg.RetryMaxMS, "maximum retry backoff in milliseconds")
+	fs.BoolVar(&flagDedup, "dedup", cfg.Dedup, "deduplicate identical images by content hash")
+	fs.StringVar(&flagReencode, "reencode", cfg.Reencode, `re-encode images to this format: "webp" (empty to skip)`)
+	fs.IntVar(&flagQuality, "quality", cfg.Quality, "re-encode quality, 1-100")
+	fs.StringVar(&flagWidths, "widths", cfg.Widths, "comma-separated responsive image widths to generate")
+	fs.BoolVar(&flagDryRun, "dry-run", cfg.DryRun, "report what would happen without writing any files")
+	fs.BoolVar(&flagVerbose, "verbose", cfg.Verbose, "log per-worker transfer progress to stderr")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if configPath != "" {
+		fileCfg, err := loadConfigFile(configPath)
+		if err != nil {
+			return err
+		}
+		cfg = fileCfg
+	}
+
+	// Flags given explicitly on the command line win over both the
+	// defaults and the config file.
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "source":
+			cfg.Source = flagSource
+		case "url":
+			cfg.URL = flagURL
+		case "file":
+			cfg.File = flagFile
+		case "basic-user":
+			cfg.BasicUser = flagBasicUser
+		case "basic-pass":
+			cfg.BasicPass = flagBasicPass
+		case "bearer-token":
+			cfg.BearerToken = flagBearerToken
+		case "hostname":
+			cfg.Hostname = flagHostname
+		case "imagedir":
+			cfg.ImageDir = flagImageDir
+		case "output":
+			cfg.Output = flagOutput
+		case "separator":
+			cfg.Separator = flagSeparator
+		case "columns":
+			cfg.Columns = flagColumns
+		case "concurrency":
+			cfg.Concurrency = flagConcurrency
+		case "max-retries":
+			cfg.MaxRetries = flagMaxRetries
+		case "retry-base-ms":
+			cfg.RetryBaseMS = flagRetryBaseMS
+		case "retry-max-ms":
+			cfg.RetryMaxMS = flagRetryMaxMS
+		case "dedup":
+			cfg.Dedup = flagDedup
+		case "reencode":
+			cfg.Reencode = flagReencode
+		case "quality":
+			cfg.Quality = flagQuality
+		case "widths":
+			cfg.Widths = flagWidths
+		case "dry-run":
+			cfg.DryRun = flagDryRun
+		case "verbose":
+			cfg.Verbose = flagVerbose
+		}
+	})
+
+	return process(cfg)
+}
+
+// loadConfigFile reads a YAML config file, layering it over defaultConfig
+// so any field the file omits keeps its usual default.
+func loadConfigFile(path string) (config, error) {
+	cfg := defaultConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+func process(cfg config) error {
+	var missingFields []string
+	switch sourceTypeLabel(cfg.Source) {
+	case "Local File":
+		if cfg.File == "" {
+			missingFields = append(missingFields, "-file")
+		}
+	default:
+		if cfg.URL == "" {
+			missingFields = append(missingFields, "-url")
+		}
+	}
+	if cfg.Hostname == "" {
+		missingFields = append(missingFields, "-hostname")
+	}
+	if cfg.ImageDir == "" {
+		missingFields = append(missingFields, "-imagedir")
+	}
+	if cfg.Output == "" {
+		missingFields = append(missingFields, "-output")
+	}
+	columns := downloader.ParseColumns(cfg.Columns)
+	if len(columns) == 0 {
+		missingFields = append(missingFields, "-columns")
+	}
+	if len(missingFields) > 0 {
+		return fmt.Errorf("missing required flags: %s", strings.Join(missingFields, ", "))
+	}
+
+	reencodeFormat := cfg.Reencode
+	if strings.EqualFold(reencodeFormat, "none") {
+		reencodeFormat = ""
+	}
+	procOpts := downloader.ProcessOptions{
+		Dedup:            cfg.Dedup,
+		ReencodeFormat:   reencodeFormat,
+		ReencodeQuality:  cfg.Quality,
+		ResponsiveWidths: downloader.ParseWidths(cfg.Widths),
+		DryRun:           cfg.DryRun,
+	}
+	retryPolicy := downloader.RetryPolicy{
+		MaxRetries: cfg.MaxRetries,
+		BaseDelay:  time.Duration(cfg.RetryBaseMS) * time.Millisecond,
+		MaxDelay:   time.Duration(cfg.RetryMaxMS) * time.Millisecond,
+	}
+
+	source := downloader.BuildInputSource(sourceTypeLabel(cfg.Source), cfg.URL, cfg.File, downloader.SeparatorRune(cfg.Separator), cfg.BasicUser, cfg.BasicPass, cfg.BearerToken)
+
+	reporter := &cliReporter{verbose: cfg.Verbose}
+
+	reporter.Status("Fetching source data...")
+	records, err := source.Fetch()
+	if err != nil {
+		return err
+	}
+
+	reporter.Status("Processing records...")
+	failures, err := downloader.ProcessRecords(records, cfg.Hostname, cfg.ImageDir, cfg.Output, cfg.Separator, cfg.Concurrency, columns, procOpts, retryPolicy, reporter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Images downloaded and data processed successfully. Output saved to %s\n", cfg.Output)
+	if failures > 0 {
+		fmt.Fprintf(os.Stderr, "%d image(s) failed; see the report for details.\n", failures)
+		os.Exit(1)
+	}
+	return nil
+}
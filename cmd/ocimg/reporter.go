@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/vitovt/OcImage2Downloader/pkg/downloader"
+)
+
+// cliReporter implements downloader.ProgressReporter for headless use: it
+// writes plain-text lines to stderr, suitable for redirecting to a log
+// file or letting a terminal overwrite in place. Overall progress is
+// logged once per whole percentage point rather than on every byte read,
+// so a redirected log stays readable; per-worker transfer detail is only
+// emitted with -verbose, since it changes many times per second.
+type cliReporter struct {
+	verbose bool
+
+	mu          sync.Mutex
+	lastOverall int
+}
+
+func (r *cliReporter) SetOverall(fraction float64) {
+	pct := int(fraction * 100)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pct == r.lastOverall {
+		return
+	}
+	r.lastOverall = pct
+	fmt.Fprintf(os.Stderr, "[%3d%%] overall progress\n", pct)
+}
+
+func (r *cliReporter) SetWorker(index int, filename string, transferred, total int64, bytesPerSecond float64) {
+	if !r.verbose {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "worker %d: %s %s/%s (%s/s)\n", index,
+		filename, downloader.FormatBytes(transferred), downloader.FormatBytes(total), downloader.FormatBytes(int64(bytesPerSecond)))
+}
+
+func (r *cliReporter) WorkerIdle(index int) {}
+
+func (r *cliReporter) Status(message string) {
+	fmt.Fprintln(os.Stderr, message)
+}
@@ -0,0 +1,474 @@
+// Package downloader implements the core image-downloading engine shared by
+// the GUI (cmd/gui) and CLI (cmd/ocimg) front ends: fetching spreadsheet
+// data from a pluggable InputSource, finding and downloading every image it
+// references, optionally post-processing those images, and rewriting the
+// spreadsheet to point at the local copies.
+package downloader
+
+import (
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// transliteration table for Ukrainian and Russian Cyrillic characters
+var cyrillicToLatin = map[rune]string{
+	// Ukrainian Cyrillic to Latin
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "H", 'Ґ': "G", 'Д': "D", 'Е': "E", 'Є': "Ye", 'Ж': "Zh",
+	'З': "Z", 'И': "Y", 'І': "I", 'Ї': "Yi", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M", 'Н': "N",
+	'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U", 'Ф': "F", 'Х': "Kh", 'Ц': "Ts",
+	'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch", 'Ю': "Yu", 'Я': "Ya", 'Ь': "",
+
+	// Lowercase Ukrainian Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e", 'є': "ye", 'ж': "zh",
+	'з': "z", 'и': "y", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+	'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ю': "yu", 'я': "ya", 'ь': "",
+
+	// Russian Cyrillic (to provide additional support)
+	'Ё': "E", 'Ы': "Y", 'Э': "E", 'ё': "e", 'ы': "y", 'э': "e",
+}
+
+// DefaultConcurrency is used when the caller does not specify a positive
+// worker count.
+const DefaultConcurrency = 8
+
+// DirExists reports whether path exists and is a directory.
+func DirExists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
+// FileExists reports whether path exists.
+func FileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !os.IsNotExist(err)
+}
+
+// ProcessRecords processes the spreadsheet data and downloads images,
+// reporting progress through reporter as it goes. It returns the number of
+// images that failed to download or process even after retries, so a
+// caller (in particular the CLI) can exit nonzero on partial failure
+// without treating the whole run as fatal.
+func ProcessRecords(records [][]string, hostname, imagedir string, outputFileName string, selectedSeparator string, concurrency int, columns []string, procOpts ProcessOptions, retryPolicy RetryPolicy, reporter ProgressReporter) (failures int, err error) {
+	if len(records) < 2 {
+		return 0, errors.New("No data in CSV")
+	}
+	if len(columns) == 0 {
+		return 0, errors.New("No content columns configured")
+	}
+
+	headers := records[0]
+	headerMap := make(map[string]int)
+	for i, h := range headers {
+		headerMap[h] = i
+	}
+
+	for _, col := range columns {
+		if _, ok := headerMap[col]; !ok {
+			return 0, fmt.Errorf("Missing required column: %s", col)
+		}
+	}
+
+	reporter.SetOverall(0)
+
+	var allImageLinks []string
+
+	// Some sources (notably .xlsx, via excelize.GetRows) drop trailing empty
+	// cells, so a data row can be shorter than the header row. Pad every row
+	// out to len(headers) up front so every subsequent row[headerMap[col]]
+	// is safe, treating a missing trailing cell as empty.
+	for i, row := range records[1:] {
+		if len(row) < len(headers) {
+			padded := make([]string, len(headers))
+			copy(padded, row)
+			records[i+1] = padded
+		}
+	}
+
+	// Collect all image links across every configured content column
+	imageLinkSet := make(map[string]struct{})
+	for _, row := range records[1:] {
+		for _, col := range columns {
+			for _, link := range extractImageLinks(row[headerMap[col]]) {
+				imageLinkSet[link] = struct{}{}
+			}
+		}
+	}
+
+	// Convert imageLinkSet to a slice
+	for link := range imageLinkSet {
+		allImageLinks = append(allImageLinks, link)
+	}
+
+	totalImages := len(allImageLinks)
+	// One step per downloaded image, plus one more per data row for the
+	// HTML-rewrite pass below, so SetOverall reaches 1.0 exactly instead of
+	// overshooting past 100% whenever there's at least one row.
+	totalSteps := totalImages + (len(records) - 1)
+	var stepsCompleted int
+	var mu sync.Mutex
+
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	manifest, err := loadManifest(manifestPath(outputFileName))
+	if err != nil {
+		return 0, err
+	}
+
+	report, err := newReport(reportJSONLPath(outputFileName))
+	if err != nil {
+		return 0, err
+	}
+	defer report.close()
+
+	reporter.Status("Downloading images...")
+	// Download images using a bounded worker pool so a large batch does not
+	// fan out thousands of simultaneous connections. Each worker drives its
+	// own row in reporter while it downloads, and falls idle between jobs.
+	processedImages := make(map[string]ProcessedImage)
+	hashes := newContentHashes()
+	var failCount int
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		worker := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range jobs {
+				filename := filepath.Base(link)
+				onProgress := func(transferred, total int64, bytesPerSecond float64) {
+					reporter.SetWorker(worker, filename, transferred, total, bytesPerSecond)
+				}
+
+				relPath, absPath, err := DownloadAndSaveImage(link, hostname, imagedir, manifest, report, retryPolicy, onProgress)
+				if err != nil {
+					reporter.Status(fmt.Sprintf("Error downloading image %s: %v", link, err))
+					reporter.WorkerIdle(worker)
+					mu.Lock()
+					failCount++
+					mu.Unlock()
+					continue
+				}
+
+				processed, err := processImage(link, absPath, relPath, procOpts, hashes, report)
+				reporter.WorkerIdle(worker)
+				if err != nil {
+					reporter.Status(fmt.Sprintf("Error processing image %s: %v", link, err))
+					report.log(ReportEvent{Action: reportActionFailed, URL: link, LocalPath: relPath, Error: err.Error()})
+					mu.Lock()
+					failCount++
+					mu.Unlock()
+					continue
+				}
+
+				mu.Lock()
+				processedImages[link] = processed
+				stepsCompleted++
+				progress := float64(stepsCompleted) / float64(totalSteps)
+				reporter.SetOverall(progress)
+				reporter.Status(fmt.Sprintf(
+					"Downloaded %d of %d images\nloading %s\n",
+					stepsCompleted,
+					totalImages,
+					link,
+				))
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, link := range allImageLinks {
+		jobs <- link
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Replace image URLs in every configured content column
+	for _, row := range records[1:] {
+		for _, col := range columns {
+			idx := headerMap[col]
+			row[idx] = replaceImageLinksWithVariants(row[idx], processedImages)
+		}
+
+		// Update progress bar
+		mu.Lock()
+		stepsCompleted++
+		progress := float64(stepsCompleted) / float64(totalSteps)
+		reporter.SetOverall(progress)
+		mu.Unlock()
+	}
+
+	// Write the modified records back to a CSV file
+	reporter.Status("Writing to output file...")
+	if err := WriteCSV(records, outputFileName, selectedSeparator); err != nil {
+		return failCount, err
+	}
+
+	if err := report.writeHTML(reportHTMLPath(outputFileName)); err != nil {
+		return failCount, err
+	}
+	return failCount, nil
+}
+
+// DownloadAndSaveImage downloads an image from a URL and saves it to the
+// desired path. It consults manifest to skip images already downloaded in a
+// previous run, retries transient failures under retryPolicy, and resumes
+// partial downloads via an HTTP Range request when the server advertises
+// "Accept-Ranges: bytes". It returns both the HTML-facing relative path and
+// the absolute path on disk, the latter needed by the optional
+// post-processing pipeline. Every skip, retry, failure, and completed fetch
+// is logged to report; onProgress, if non-nil, is called as bytes arrive.
+func DownloadAndSaveImage(imageURL, hostname, imagedir string, manifest *Manifest, report *Report, retryPolicy RetryPolicy, onProgress func(transferred, total int64, bytesPerSecond float64)) (relativePath, absolutePath string, err error) {
+	// Prepare the image URL
+	if strings.HasPrefix(imageURL, "//") {
+		imageURL = "https:" + imageURL
+	} else if !strings.HasPrefix(imageURL, "http") {
+		imageURL = strings.TrimRight(hostname, "/") + "/" + strings.TrimLeft(imageURL, "/")
+	}
+
+	// Prepare the filename
+	parsedURL, err := url.Parse(imageURL)
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid image URL: %v", err)
+	}
+
+	// Extract the filename and make it unique by appending part of the URL path
+	pathParts := strings.Split(parsedURL.Path, "/")
+	uniquePart := ""
+	if len(pathParts) >= 3 {
+		// Use the last two directories if available
+		uniquePart = strings.Join(pathParts[len(pathParts)-3:len(pathParts)-1], "_")
+	} else if len(pathParts) >= 2 {
+		// Use the last directory if only one directory exists
+		uniquePart = pathParts[len(pathParts)-2]
+	}
+
+	filenameWithExt := filepath.Base(parsedURL.Path)
+	filenameWithExt = strings.Split(filenameWithExt, "?")[0] // Remove query params
+	extension := filepath.Ext(filenameWithExt)
+	if extension == "" {
+		extension = ".jpg" // Default to 'jpg' if no extension is present
+	}
+	filename := strings.TrimSuffix(filenameWithExt, filepath.Ext(filenameWithExt))
+
+	// Generate a SHA-1 hash of the URL to make the filename unique
+	hasher := sha1.New()
+	hasher.Write([]byte(imageURL))
+	hash := hex.EncodeToString(hasher.Sum(nil))[:4] // Shorten the hash to 4 characters
+
+	// Combine the unique part with the filename
+	filename = hash + "-" + uniquePart + "-" + filename
+	filename = Transliterate(filename)
+	filename = filename + extension
+
+	// Ensure 'files' directory exists
+	imageDirPath := filepath.Join("files", imagedir)
+	err = os.MkdirAll(imageDirPath, os.ModePerm)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to create directory: %v", err)
+	}
+
+	filePath := filepath.Join(imageDirPath, filename)
+	relativePath = filepath.ToSlash(imagedir + filename) // For replacement in HTML
+
+	// Skip entirely if the manifest already recorded a completed download.
+	if entry, ok := manifest.Get(imageURL); ok && entry.Status == manifestStatusDone {
+		if _, err := os.Stat(filePath); err == nil {
+			report.log(ReportEvent{Action: reportActionSkipped, URL: imageURL, LocalPath: entry.LocalPath})
+			return entry.LocalPath, filePath, nil
+		}
+	}
+
+	client := &http.Client{}
+	downloadStart := time.Now()
+
+	attempt := func() error {
+		// Resume a previously interrupted download if we have a partial
+		// file on disk and the server supports range requests.
+		var startOffset int64
+		if stat, err := os.Stat(filePath); err == nil {
+			if entry, ok := manifest.Get(imageURL); ok && entry.Status == manifestStatusPartial {
+				startOffset = stat.Size()
+			}
+		}
+
+		req, err := http.NewRequest("GET", imageURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create HTTP request: %v", err)
+		}
+		req.Header.Set("User-Agent", "Mozilla/5.0 (compatible)")
+		req.Header.Set("Accept", "*/*")
+		if startOffset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", startOffset))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return &retryableError{err: fmt.Errorf("failed to execute HTTP request: %v", err)}
+		}
+		defer resp.Body.Close()
+
+		if isTransientStatus(resp.StatusCode) {
+			delay, _ := parseRetryAfter(resp.Header.Get("Retry-After"))
+			return &retryableError{err: fmt.Errorf("failed to download image: %s", resp.Status), retryAfter: delay}
+		}
+
+		resuming := startOffset > 0 && resp.StatusCode == http.StatusPartialContent && resp.Header.Get("Accept-Ranges") != ""
+		if startOffset > 0 && !resuming {
+			// Server ignored our Range request; restart from scratch.
+			startOffset = 0
+		}
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+			return fmt.Errorf("failed to download image: %s", resp.Status)
+		}
+
+		flags := os.O_CREATE | os.O_WRONLY
+		if resuming {
+			flags |= os.O_APPEND
+		} else {
+			flags |= os.O_TRUNC
+		}
+		out, err := os.OpenFile(filePath, flags, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %v", err)
+		}
+		defer out.Close()
+
+		total := startOffset + resp.ContentLength
+		body := io.Reader(resp.Body)
+		if onProgress != nil {
+			body = newProgressReader(resp.Body, total, func(transferred, total int64, rate float64) {
+				onProgress(startOffset+transferred, total, rate)
+			})
+		}
+
+		if _, err := io.Copy(out, body); err != nil {
+			manifest.Set(ManifestEntry{URL: imageURL, LocalPath: relativePath, Status: manifestStatusPartial})
+			return &retryableError{err: fmt.Errorf("failed to save image to file: %v", err)}
+		}
+		return nil
+	}
+
+	onRetry := func(retryErr error) {
+		report.log(ReportEvent{Action: reportActionRetried, URL: imageURL, Error: retryErr.Error()})
+	}
+
+	if err := withRetry(retryPolicy, attempt, onRetry); err != nil {
+		manifest.Set(ManifestEntry{URL: imageURL, LocalPath: relativePath, Status: manifestStatusFailed})
+		report.log(ReportEvent{Action: reportActionFailed, URL: imageURL, Error: err.Error()})
+		return "", "", err
+	}
+
+	sha1sum, size, err := hashFile(filePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to hash downloaded file: %v", err)
+	}
+
+	if err := manifest.Set(ManifestEntry{
+		URL:       imageURL,
+		LocalPath: relativePath,
+		SHA1:      sha1sum,
+		Size:      size,
+		Status:    manifestStatusDone,
+	}); err != nil {
+		return "", "", err
+	}
+
+	report.log(ReportEvent{Action: reportActionFetched, URL: imageURL, LocalPath: relativePath, Bytes: size, Seconds: time.Since(downloadStart).Seconds()})
+	return relativePath, filePath, nil
+}
+
+// hashFile returns the SHA-1 hex digest and size in bytes of the file at path.
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	hasher := sha1.New()
+	size, err := io.Copy(hasher, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+// WriteCSV writes the modified records back to a CSV file.
+func WriteCSV(records [][]string, outputFileName string, selectedSeparator string) error {
+	file, err := os.Create(outputFileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Set the separator based on user selection
+	switch selectedSeparator {
+	case "Comma (,)":
+		writer.Comma = ','
+	case "Semicolon (;)":
+		writer.Comma = ';'
+	case "Tab (\\t)":
+		writer.Comma = '\t'
+	}
+
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Transliterate converts Cyrillic characters to Latin and cleans up the filename
+func Transliterate(filename string) string {
+	var builder strings.Builder
+
+	// Step 1: Transliterate each character
+	for _, char := range filename {
+		if latin, found := cyrillicToLatin[char]; found {
+			builder.WriteString(latin)
+		} else {
+			builder.WriteRune(char) // Keep original character if not Cyrillic
+		}
+	}
+
+	// Step 2: Replace forbidden symbols with a dash
+	transliterated := builder.String()
+	transliterated = strings.ReplaceAll(transliterated, " ", "-")                      // Replace spaces with '-'
+	transliterated = regexp.MustCompile(`[^\w-]`).ReplaceAllString(transliterated, "") // Remove all non-word chars except '-'
+
+	// Step 3: Ensure only ASCII letters, numbers, and '-' remain
+	transliterated = strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r // Keep ASCII letters, numbers, and '-'
+		}
+		return -1 // Remove other characters
+	}, transliterated)
+
+	// Step 4: Return the cleaned-up filename
+	return strings.ToLower(transliterated) // Convert the final filename to lowercase
+}
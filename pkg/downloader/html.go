@@ -0,0 +1,264 @@
+package downloader
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// imageExtensions lists file extensions treated as images when found in <a href="...">
+// gallery links.
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".bmp", ".svg", ".tiff"}
+
+// backgroundImageRe matches url(...) references inside a CSS background-image
+// declaration, with or without surrounding quotes.
+var backgroundImageRe = regexp.MustCompile(`background-image\s*:\s*url\(\s*['"]?([^'")]+)['"]?\s*\)`)
+
+// extractImageLinks walks htmlContent with a streaming tokenizer and collects
+// every image URL it can find: <img src>, <img>/<source> srcset candidates
+// (inside <picture> or standalone), <a href> gallery links pointing at an
+// image file, and CSS background-image: url(...) references in style
+// attributes. Using a real parser (instead of a single src= regex) means
+// responsive image sets and gallery-style markup are no longer silently
+// dropped.
+func extractImageLinks(htmlContent string) []string {
+	z := html.NewTokenizer(strings.NewReader(htmlContent))
+	seen := make(map[string]struct{})
+	var links []string
+
+	add := func(link string) {
+		link = strings.TrimSpace(link)
+		if link == "" {
+			return
+		}
+		if _, ok := seen[link]; ok {
+			return
+		}
+		seen[link] = struct{}{}
+		links = append(links, link)
+	}
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			for _, link := range imageLinksForToken(tok) {
+				add(link)
+			}
+		}
+	}
+}
+
+// imageLinksForToken returns every image URL referenced by a single start
+// (or self-closing) tag token.
+func imageLinksForToken(tok html.Token) []string {
+	var links []string
+	for _, attr := range tok.Attr {
+		switch {
+		case attr.Key == "src" && (tok.DataAtom == atom.Img):
+			links = append(links, attr.Val)
+		case attr.Key == "srcset" && (tok.DataAtom == atom.Img || tok.DataAtom == atom.Source):
+			links = append(links, parseSrcset(attr.Val)...)
+		case attr.Key == "href" && tok.DataAtom == atom.A && hasImageExtension(attr.Val):
+			links = append(links, attr.Val)
+		case attr.Key == "style":
+			for _, m := range backgroundImageRe.FindAllStringSubmatch(attr.Val, -1) {
+				links = append(links, m[1])
+			}
+		}
+	}
+	return links
+}
+
+// parseSrcset splits a srcset attribute value ("url1 1x, url2 480w, url3")
+// into its individual candidate URLs, discarding the width/density
+// descriptors.
+func parseSrcset(srcset string) []string {
+	var urls []string
+	for _, candidate := range strings.Split(srcset, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		urls = append(urls, fields[0])
+	}
+	return urls
+}
+
+// hasImageExtension reports whether rawURL's path ends in a known image
+// file extension, ignoring any query string or fragment.
+func hasImageExtension(rawURL string) bool {
+	clean := rawURL
+	if i := strings.IndexAny(clean, "?#"); i != -1 {
+		clean = clean[:i]
+	}
+	lower := strings.ToLower(clean)
+	for _, ext := range imageExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteTokenAttrs replaces image URLs referenced by tok's attributes in
+// place, according to imagePathMap, and reports whether it changed any
+// attribute. Callers use that to decide whether the token needs
+// re-serializing at all, since html.Token.String() normalizes casing, quote
+// style, and boolean attributes repo-wide, not just the attributes touched
+// here.
+func rewriteTokenAttrs(tok *html.Token, imagePathMap map[string]string) bool {
+	changed := false
+	for i, attr := range tok.Attr {
+		switch {
+		case attr.Key == "src" && tok.DataAtom == atom.Img:
+			if newPath, ok := imagePathMap[attr.Val]; ok {
+				tok.Attr[i].Val = newPath
+				changed = true
+			}
+		case attr.Key == "srcset" && (tok.DataAtom == atom.Img || tok.DataAtom == atom.Source):
+			if newVal := rewriteSrcset(attr.Val, imagePathMap); newVal != attr.Val {
+				tok.Attr[i].Val = newVal
+				changed = true
+			}
+		case attr.Key == "href" && tok.DataAtom == atom.A && hasImageExtension(attr.Val):
+			if newPath, ok := imagePathMap[attr.Val]; ok {
+				tok.Attr[i].Val = newPath
+				changed = true
+			}
+		case attr.Key == "style":
+			if newVal := backgroundImageRe.ReplaceAllStringFunc(attr.Val, func(decl string) string {
+				m := backgroundImageRe.FindStringSubmatch(decl)
+				if len(m) < 2 {
+					return decl
+				}
+				newPath, ok := imagePathMap[m[1]]
+				if !ok {
+					return decl
+				}
+				return strings.Replace(decl, m[1], newPath, 1)
+			}); newVal != attr.Val {
+				tok.Attr[i].Val = newVal
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// replaceImageLinksWithVariants behaves like replaceImageLinks, but for
+// every <img src="..."> whose processed result carries responsive
+// Variants, it wraps the tag in a <picture><source srcset="..."> block
+// listing each width variant, falling back to the original <img> (now
+// pointed at FinalPath) for browsers without <picture> support.
+func replaceImageLinksWithVariants(htmlContent string, results map[string]ProcessedImage) string {
+	imagePathMap := make(map[string]string, len(results))
+	for url, r := range results {
+		imagePathMap[url] = r.FinalPath
+	}
+
+	z := html.NewTokenizer(strings.NewReader(htmlContent))
+	var out strings.Builder
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return out.String()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			raw := z.Raw()
+			tok := z.Token()
+			if tok.DataAtom == atom.Img {
+				if picture, ok := pictureMarkup(tok, results); ok {
+					out.WriteString(picture)
+					continue
+				}
+			}
+			if rewriteTokenAttrs(&tok, imagePathMap) {
+				out.WriteString(tok.String())
+			} else {
+				out.Write(raw)
+			}
+		default:
+			out.Write(z.Raw())
+		}
+	}
+}
+
+// pictureMarkup builds a <picture><source srcset="...">IMG</picture> block
+// for an <img> tag whose src has responsive variants, or reports ok=false
+// if no such rewrite applies (no matching result, or no variants).
+func pictureMarkup(tok html.Token, results map[string]ProcessedImage) (string, bool) {
+	var src string
+	for _, attr := range tok.Attr {
+		if attr.Key == "src" {
+			src = attr.Val
+			break
+		}
+	}
+	result, ok := results[src]
+	if !ok || len(result.Variants) == 0 {
+		return "", false
+	}
+
+	widths := make([]int, 0, len(result.Variants))
+	for w := range result.Variants {
+		widths = append(widths, w)
+	}
+	sort.Ints(widths)
+
+	candidates := make([]string, 0, len(widths))
+	for _, w := range widths {
+		candidates = append(candidates, fmt.Sprintf("%s %dw", result.Variants[w], w))
+	}
+
+	imgTok := tok
+	imgTok.Attr = append([]html.Attribute(nil), tok.Attr...)
+	for i, attr := range imgTok.Attr {
+		if attr.Key == "src" {
+			imgTok.Attr[i].Val = result.FinalPath
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("<picture>")
+	fmt.Fprintf(&b, `<source srcset="%s">`, strings.Join(candidates, ", "))
+	b.WriteString(imgTok.String())
+	b.WriteString("</picture>")
+	return b.String(), true
+}
+
+// rewriteSrcset replaces the URL portion of each srcset candidate, leaving
+// its width/density descriptor (if any) untouched.
+func rewriteSrcset(srcset string, imagePathMap map[string]string) string {
+	parts := strings.Split(srcset, ",")
+	for i, candidate := range parts {
+		trimmed := strings.TrimSpace(candidate)
+		if trimmed == "" {
+			continue
+		}
+		fields := strings.Fields(trimmed)
+		newPath, ok := imagePathMap[fields[0]]
+		if !ok {
+			continue
+		}
+		fields[0] = newPath
+		rewritten := strings.Join(fields, " ")
+		if i > 0 {
+			rewritten = " " + rewritten
+		}
+		parts[i] = rewritten
+	}
+	return strings.Join(parts, ",")
+}
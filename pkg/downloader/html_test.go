@@ -0,0 +1,80 @@
+package downloader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractImageLinksFindsEverySource(t *testing.T) {
+	htmlContent := `
+		<p><img src="a.jpg"></p>
+		<picture><source srcset="b-320.webp 320w, b-640.webp 640w"><img src="b.webp"></picture>
+		<a href="gallery.png">gallery</a>
+		<div style="background-image: url('bg.jpg')"></div>
+	`
+	got := extractImageLinks(htmlContent)
+	want := []string{"a.jpg", "b-320.webp", "b-640.webp", "b.webp", "gallery.png", "bg.jpg"}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, link := range want {
+		if got[i] != link {
+			t.Errorf("link %d = %q, want %q (full: %v)", i, got[i], link, got)
+		}
+	}
+}
+
+func TestExtractImageLinksDeduplicates(t *testing.T) {
+	htmlContent := `<img src="a.jpg"><img src="a.jpg">`
+	got := extractImageLinks(htmlContent)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 deduplicated link, got %v", got)
+	}
+}
+
+func TestReplaceImageLinksWithVariantsPreservesUntouchedMarkup(t *testing.T) {
+	htmlContent := `<DIV CLASS='keep'><img src="a.jpg"></DIV>`
+	results := map[string]ProcessedImage{
+		"a.jpg": {FinalPath: "run/a.jpg"},
+	}
+	got := replaceImageLinksWithVariants(htmlContent, results)
+
+	if want := `<DIV CLASS='keep'>`; !strings.Contains(got, want) {
+		t.Errorf("expected untouched tag to keep its original casing/quoting, got: %s", got)
+	}
+	if want := `</DIV>`; !strings.Contains(got, want) {
+		t.Errorf("expected untouched end tag to survive raw, got: %s", got)
+	}
+	if want := `src="run/a.jpg"`; !strings.Contains(got, want) {
+		t.Errorf("expected rewritten src, got: %s", got)
+	}
+}
+
+func TestReplaceImageLinksWithVariantsWrapsPicture(t *testing.T) {
+	htmlContent := `<img src="a.jpg">`
+	results := map[string]ProcessedImage{
+		"a.jpg": {FinalPath: "run/a.jpg", Variants: map[int]string{320: "run/a-320w.jpg"}},
+	}
+	got := replaceImageLinksWithVariants(htmlContent, results)
+
+	for _, want := range []string{"<picture>", `srcset="run/a-320w.jpg 320w"`, `src="run/a.jpg"`, "</picture>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got: %s", want, got)
+		}
+	}
+}
+
+func TestRewriteSrcsetOnlySeparatesJoinedCandidates(t *testing.T) {
+	imagePathMap := map[string]string{"b.png": "run/b.png"}
+
+	got := rewriteSrcset("b.png 480w", imagePathMap)
+	if want := "run/b.png 480w"; got != want {
+		t.Errorf("single candidate: got %q, want %q (no leading space)", got, want)
+	}
+
+	got = rewriteSrcset("a.png 320w, b.png 480w", imagePathMap)
+	if want := "a.png 320w, run/b.png 480w"; got != want {
+		t.Errorf("second candidate: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,298 @@
+package downloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chai2010/webp"
+	"github.com/nfnt/resize"
+)
+
+// ProcessOptions configures the optional post-download image pipeline:
+// content-hash deduplication, re-encoding to a modern format, and
+// generation of responsive width variants. An empty ProcessOptions
+// disables the pipeline entirely, leaving downloaded files untouched.
+type ProcessOptions struct {
+	Dedup            bool
+	ReencodeFormat   string // "", "webp", or "avif"
+	ReencodeQuality  int    // 1-100; only used when ReencodeFormat is set
+	ResponsiveWidths []int
+	DryRun           bool
+}
+
+// Enabled reports whether any processing stage is configured.
+func (o ProcessOptions) Enabled() bool {
+	return o.Dedup || o.ReencodeFormat != "" || len(o.ResponsiveWidths) > 0
+}
+
+// ProcessedImage describes the outcome of running one downloaded file
+// through the post-processing pipeline.
+type ProcessedImage struct {
+	// FinalPath is the local relative path HTML references should be
+	// rewritten to.
+	FinalPath string
+	// Variants maps a responsive width to the local relative path of the
+	// generated variant at that width.
+	Variants map[int]string
+	// Deduplicated is true if this download's content matched a
+	// previously-processed file and was collapsed onto it.
+	Deduplicated bool
+}
+
+// ParseWidths splits a comma-separated list of responsive widths (in
+// pixels), ignoring blank entries and anything that doesn't parse as a
+// positive integer.
+func ParseWidths(raw string) []int {
+	var widths []int
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		width, err := strconv.Atoi(field)
+		if err != nil || width <= 0 {
+			continue
+		}
+		widths = append(widths, width)
+	}
+	return widths
+}
+
+// contentHashes tracks the SHA-256 digest of every processed file, so two
+// different URLs that serve identical bytes collapse onto the same final
+// file and the same HTML replacement path. The first URL to claim a digest
+// runs the rest of the pipeline (reencode, responsive variants); any later
+// URL with the same digest blocks until that run finishes and reuses its
+// full result (final path and any responsive Variants), rather than the raw
+// download path that run started from and may since have renamed.
+type contentHashes struct {
+	mu     sync.Mutex
+	byHash map[string]*claimedHash
+}
+
+type claimedHash struct {
+	done   chan struct{}
+	result ProcessedImage
+}
+
+func newContentHashes() *contentHashes {
+	return &contentHashes{byHash: make(map[string]*claimedHash)}
+}
+
+// claim registers digest as being processed by the caller, unless some
+// earlier call already claimed it. If duplicate is true, claim has already
+// blocked until that earlier call finished and existing is its complete
+// result. Otherwise the caller must invoke the returned finish func exactly
+// once, with its own result on success or the zero ProcessedImage if it
+// failed, to unblock anyone waiting on the same digest; a zero-value
+// release abandons the claim so the next waiter (if any) retries the digest
+// itself instead of waiting forever.
+func (h *contentHashes) claim(digest string) (existing ProcessedImage, duplicate bool, finish func(result ProcessedImage)) {
+	h.mu.Lock()
+	if entry, ok := h.byHash[digest]; ok {
+		h.mu.Unlock()
+		<-entry.done
+		if entry.result.FinalPath == "" {
+			return h.claim(digest)
+		}
+		return entry.result, true, nil
+	}
+	entry := &claimedHash{done: make(chan struct{})}
+	h.byHash[digest] = entry
+	h.mu.Unlock()
+	return ProcessedImage{}, false, func(result ProcessedImage) {
+		h.mu.Lock()
+		if result.FinalPath == "" {
+			delete(h.byHash, digest)
+		} else {
+			entry.result = result
+		}
+		h.mu.Unlock()
+		close(entry.done)
+	}
+}
+
+// encodableFormats lists the extensions (without the leading dot) that
+// encodeImage can actually write. Responsive-variant generation skips
+// source images in any other format instead of erroring the whole image
+// out, since a failure to produce smaller variants shouldn't throw away an
+// otherwise-successful download.
+var encodableFormats = map[string]bool{
+	"jpg": true, "jpeg": true, "png": true, "webp": true, "gif": true,
+}
+
+// processImage runs the pipeline configured by opts against the file at
+// filePath (whose HTML-facing path is relativePath). With DryRun set, no
+// files are written or removed, but the paths that would result are still
+// reported so the caller can surface what would happen. If the file is
+// deduplicated against an earlier download, that outcome is logged to
+// report under imageURL.
+func processImage(imageURL, filePath, relativePath string, opts ProcessOptions, hashes *contentHashes, report *Report) (result ProcessedImage, err error) {
+	if !opts.Enabled() {
+		return ProcessedImage{FinalPath: relativePath}, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return ProcessedImage{}, fmt.Errorf("failed to read %s for processing: %v", filePath, err)
+	}
+
+	var finishClaim func(result ProcessedImage)
+	if opts.Dedup {
+		sum := sha256.Sum256(data)
+		digest := hex.EncodeToString(sum[:])
+		existing, duplicate, finish := hashes.claim(digest)
+		if duplicate {
+			// The canonical copy already owns this content; leave this
+			// URL's own downloaded file on disk (the manifest and report
+			// still index it by this path) and just point the HTML
+			// rewrite at the canonical result, inheriting its Variants.
+			report.log(ReportEvent{Action: reportActionDeduplicated, URL: imageURL, LocalPath: existing.FinalPath})
+			return ProcessedImage{FinalPath: existing.FinalPath, Variants: existing.Variants, Deduplicated: true}, nil
+		}
+		finishClaim = finish
+		// Whatever happens below, release the claim: the full result on
+		// success, or the zero value on any error so a waiting duplicate
+		// retries instead of blocking forever on a claim that never
+		// finishes.
+		defer func() {
+			if err != nil {
+				finishClaim(ProcessedImage{})
+			} else {
+				finishClaim(result)
+			}
+		}()
+	}
+
+	result = ProcessedImage{FinalPath: relativePath}
+	if opts.ReencodeFormat == "" && len(opts.ResponsiveWidths) == 0 {
+		return result, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, fmt.Errorf("failed to decode %s for processing: %v", filePath, err)
+	}
+
+	if opts.ReencodeFormat != "" {
+		newPath, newRelative, err := reencodeImage(filePath, relativePath, img, opts.ReencodeFormat, opts.ReencodeQuality, opts.DryRun)
+		if err != nil {
+			return ProcessedImage{}, err
+		}
+		if !opts.DryRun && newPath != filePath {
+			os.Remove(filePath)
+		}
+		filePath = newPath
+		result.FinalPath = newRelative
+	}
+
+	if len(opts.ResponsiveWidths) > 0 {
+		variantFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), ".")
+		if !encodableFormats[variantFormat] {
+			report.log(ReportEvent{Action: reportActionSkipped, URL: imageURL, LocalPath: result.FinalPath, Error: fmt.Sprintf("responsive variants not generated: no encoder for %q", variantFormat)})
+		} else {
+			variants, err := generateResponsiveVariants(filePath, result.FinalPath, img, opts.ResponsiveWidths, opts.DryRun)
+			if err != nil {
+				return ProcessedImage{}, err
+			}
+			result.Variants = variants
+		}
+	}
+
+	return result, nil
+}
+
+// reencodeImage writes img to a new file next to filePath using the
+// requested format, returning the new file's absolute and HTML-relative
+// paths. In dry-run mode no file is written.
+func reencodeImage(filePath, relativePath string, img image.Image, format string, quality int, dryRun bool) (string, string, error) {
+	ext := "." + format
+	newPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ext
+	newRelative := strings.TrimSuffix(relativePath, filepath.Ext(relativePath)) + ext
+
+	if dryRun {
+		return newPath, newRelative, nil
+	}
+
+	out, err := os.Create(newPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create %s: %v", newPath, err)
+	}
+	defer out.Close()
+
+	if err := encodeImage(out, img, format, quality); err != nil {
+		return "", "", fmt.Errorf("failed to encode %s as %s: %v", newPath, format, err)
+	}
+	return newPath, newRelative, nil
+}
+
+// encodeImage writes img to w in the given format. "avif" is accepted by
+// the UI but not yet implemented, since no pure-Go/cgo-free AVIF encoder
+// is available; callers get a clear error instead of a silently wrong file.
+// Formats with no encoder here (e.g. "bmp", "tiff") also return an error
+// rather than silently writing a JPEG under the wrong extension.
+func encodeImage(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case "webp":
+		if quality <= 0 {
+			quality = 80
+		}
+		return webp.Encode(w, img, &webp.Options{Quality: float32(quality)})
+	case "avif":
+		return errors.New("avif re-encoding is not yet supported")
+	case "png":
+		return png.Encode(w, img)
+	case "jpg", "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	case "gif":
+		return gif.Encode(w, img, nil)
+	default:
+		return fmt.Errorf("unsupported image format %q", format)
+	}
+}
+
+// generateResponsiveVariants resizes img to each requested width, saving
+// "<base>-<width>w<ext>" next to filePath and returning a width -> local
+// relative path map for building a <picture> srcset. In dry-run mode no
+// files are written, but the intended paths are still reported.
+func generateResponsiveVariants(filePath, relativePath string, img image.Image, widths []int, dryRun bool) (map[int]string, error) {
+	ext := filepath.Ext(filePath)
+	base := strings.TrimSuffix(filePath, ext)
+	relBase := strings.TrimSuffix(relativePath, ext)
+
+	variants := make(map[int]string, len(widths))
+	for _, width := range widths {
+		variantPath := fmt.Sprintf("%s-%dw%s", base, width, ext)
+		variantRelative := fmt.Sprintf("%s-%dw%s", relBase, width, ext)
+		variants[width] = variantRelative
+
+		if dryRun {
+			continue
+		}
+
+		resized := resize.Resize(uint(width), 0, img, resize.Lanczos3)
+		out, err := os.Create(variantPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %v", variantPath, err)
+		}
+		err = encodeImage(out, resized, strings.TrimPrefix(strings.ToLower(ext), "."), 85)
+		out.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %v", variantPath, err)
+		}
+	}
+	return variants, nil
+}
@@ -0,0 +1,79 @@
+package downloader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContentHashesClaimReturnsCanonicalResultToDuplicate(t *testing.T) {
+	h := newContentHashes()
+
+	existing, duplicate, finish := h.claim("digest")
+	if duplicate {
+		t.Fatalf("first claim should not be a duplicate, got existing=%+v", existing)
+	}
+
+	canonical := ProcessedImage{FinalPath: "run/canonical.webp", Variants: map[int]string{320: "run/canonical-320w.webp"}}
+	finish(canonical)
+
+	got, duplicate, _ := h.claim("digest")
+	if !duplicate {
+		t.Fatal("second claim on the same digest should be a duplicate")
+	}
+	if got.FinalPath != canonical.FinalPath {
+		t.Errorf("FinalPath = %q, want %q", got.FinalPath, canonical.FinalPath)
+	}
+	if got.Variants[320] != canonical.Variants[320] {
+		t.Errorf("Variants not inherited: got %+v, want %+v", got.Variants, canonical.Variants)
+	}
+}
+
+func TestContentHashesClaimReleaseOnFailureLetsNextWaiterReclaim(t *testing.T) {
+	h := newContentHashes()
+
+	_, duplicate, finish := h.claim("digest")
+	if duplicate {
+		t.Fatal("first claim should not be a duplicate")
+	}
+	finish(ProcessedImage{}) // simulate the first claimant's processing failing
+
+	_, duplicate, finish2 := h.claim("digest")
+	if duplicate {
+		t.Fatal("a claim abandoned by a zero-value finish should let the next caller claim it fresh")
+	}
+	if finish2 == nil {
+		t.Fatal("a fresh (non-duplicate) claim must return a finish func")
+	}
+}
+
+func TestContentHashesClaimBlocksUntilFinish(t *testing.T) {
+	h := newContentHashes()
+
+	_, duplicate, finish := h.claim("digest")
+	if duplicate {
+		t.Fatal("first claim should not be a duplicate")
+	}
+
+	done := make(chan ProcessedImage, 1)
+	go func() {
+		existing, _, _ := h.claim("digest")
+		done <- existing
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("waiter returned before the claim was finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	finish(ProcessedImage{FinalPath: "run/canonical.png"})
+
+	select {
+	case got := <-done:
+		if got.FinalPath != "run/canonical.png" {
+			t.Errorf("FinalPath = %q, want run/canonical.png", got.FinalPath)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter never unblocked after finish")
+	}
+}
@@ -0,0 +1,96 @@
+package downloader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Manifest status values recorded for each downloaded URL.
+const (
+	manifestStatusDone    = "done"
+	manifestStatusPartial = "partial"
+	manifestStatusFailed  = "failed"
+)
+
+// ManifestEntry records the outcome of downloading a single source URL, so
+// a later run of the same output file can resume instead of starting over.
+type ManifestEntry struct {
+	URL       string `json:"url"`
+	LocalPath string `json:"local_path"`
+	SHA1      string `json:"sha1"`
+	Size      int64  `json:"size"`
+	Status    string `json:"status"`
+}
+
+// Manifest is a small JSON-backed ledger, keyed by source URL, that lets
+// downloadAndSaveImage skip already-completed downloads and resume
+// partially-downloaded ones across runs.
+type Manifest struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]ManifestEntry
+}
+
+// manifestPath returns the manifest file that sits alongside outputFileName,
+// e.g. "output.csv" -> "output.manifest.json".
+func manifestPath(outputFileName string) string {
+	ext := filepath.Ext(outputFileName)
+	base := strings.TrimSuffix(outputFileName, ext)
+	return base + ".manifest.json"
+}
+
+// loadManifest reads the manifest at path if it exists, or returns an empty
+// one ready to be populated and saved.
+func loadManifest(path string) (*Manifest, error) {
+	m := &Manifest{path: path, entries: make(map[string]ManifestEntry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %v", err)
+	}
+	if len(data) == 0 {
+		return m, nil
+	}
+	if err := json.Unmarshal(data, &m.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+	return m, nil
+}
+
+// Get returns the recorded entry for url, if any.
+func (m *Manifest) Get(url string) (ManifestEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[url]
+	return entry, ok
+}
+
+// Set records (or replaces) the entry for its URL and persists the
+// manifest to disk immediately, so progress survives a crash mid-batch.
+func (m *Manifest) Set(entry ManifestEntry) error {
+	m.mu.Lock()
+	m.entries[entry.URL] = entry
+	m.mu.Unlock()
+	return m.save()
+}
+
+// save writes the manifest to disk. Callers must not hold m.mu.
+func (m *Manifest) save() error {
+	m.mu.Lock()
+	data, err := json.MarshalIndent(m.entries, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %v", err)
+	}
+	if err := os.WriteFile(m.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressReader wraps an io.Reader, invoking onRead after every read with
+// cumulative bytes transferred, the expected total (0 if unknown), and the
+// current transfer rate in bytes per second.
+type progressReader struct {
+	io.Reader
+	total   int64
+	read    int64
+	started time.Time
+	onRead  func(transferred, total int64, bytesPerSecond float64)
+}
+
+func newProgressReader(r io.Reader, total int64, onRead func(transferred, total int64, bytesPerSecond float64)) *progressReader {
+	return &progressReader{Reader: r, total: total, started: time.Now(), onRead: onRead}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.Reader.Read(b)
+	if n > 0 && p.onRead != nil {
+		p.read += int64(n)
+		elapsed := time.Since(p.started).Seconds()
+		var rate float64
+		if elapsed > 0 {
+			rate = float64(p.read) / elapsed
+		}
+		p.onRead(p.read, p.total, rate)
+	}
+	return n, err
+}
+
+// FormatBytes renders n bytes as a short human-readable size (e.g. "4.2 MiB").
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
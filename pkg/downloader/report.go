@@ -0,0 +1,148 @@
+package downloader
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Report actions recorded for every image URL processed during a run.
+const (
+	reportActionFetched      = "fetched"
+	reportActionSkipped      = "skipped"
+	reportActionRetried      = "retried"
+	reportActionFailed       = "failed"
+	reportActionDeduplicated = "deduplicated"
+)
+
+// ReportEvent is one structured log line describing a single action taken
+// against one image URL.
+type ReportEvent struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	URL       string    `json:"url"`
+	LocalPath string    `json:"local_path,omitempty"`
+	Bytes     int64     `json:"bytes,omitempty"`
+	Seconds   float64   `json:"seconds,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Report appends every ReportEvent to a JSONL file as it happens, and can
+// render a human-readable HTML summary once processing completes. This
+// makes a large batch auditable instead of relying on stdout, which the
+// GUI user never sees.
+type Report struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	events  []ReportEvent
+	start   time.Time
+}
+
+// reportJSONLPath returns the structured log file that sits alongside
+// outputFileName, e.g. "output.csv" -> "output.report.jsonl".
+func reportJSONLPath(outputFileName string) string {
+	ext := filepath.Ext(outputFileName)
+	base := strings.TrimSuffix(outputFileName, ext)
+	return base + ".report.jsonl"
+}
+
+// reportHTMLPath returns the human-readable summary file that sits
+// alongside outputFileName, e.g. "output.csv" -> "output.report.html".
+func reportHTMLPath(outputFileName string) string {
+	ext := filepath.Ext(outputFileName)
+	base := strings.TrimSuffix(outputFileName, ext)
+	return base + ".report.html"
+}
+
+// newReport creates (or truncates) the JSONL log at path.
+func newReport(path string) (*Report, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report file: %v", err)
+	}
+	return &Report{file: f, encoder: json.NewEncoder(f), start: time.Now()}, nil
+}
+
+// log appends event to the JSONL file, stamping its time, and keeps it in
+// memory for the eventual HTML summary.
+func (r *Report) log(event ReportEvent) {
+	event.Time = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	if err := r.encoder.Encode(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report event: %v\n", err)
+	}
+}
+
+// close closes the underlying JSONL file. Callers must not hold r.mu.
+func (r *Report) close() error {
+	return r.file.Close()
+}
+
+// writeHTML renders a human-readable summary of every logged event to
+// path: action counts, failures with their reasons, total bytes and
+// elapsed time, and thumbnails of every fetched or deduplicated image.
+func (r *Report) writeHTML(path string) error {
+	r.mu.Lock()
+	events := append([]ReportEvent(nil), r.events...)
+	r.mu.Unlock()
+
+	var totalBytes int64
+	counts := make(map[string]int)
+	var failures []ReportEvent
+	var thumbnails []ReportEvent
+	for _, e := range events {
+		counts[e.Action]++
+		totalBytes += e.Bytes
+		switch e.Action {
+		case reportActionFailed:
+			failures = append(failures, e)
+		case reportActionFetched, reportActionDeduplicated:
+			if e.LocalPath != "" {
+				thumbnails = append(thumbnails, e)
+			}
+		}
+	}
+	sort.Slice(thumbnails, func(i, j int) bool { return thumbnails[i].URL < thumbnails[j].URL })
+	elapsed := time.Since(r.start).Round(time.Second)
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>Download Report</title>\n")
+	b.WriteString("<style>body{font-family:sans-serif;margin:2em;}img{max-width:120px;max-height:120px;margin:4px;border:1px solid #ccc;}.failure{color:#a00;}</style>\n")
+	b.WriteString("</head><body>\n<h1>Download Report</h1>\n")
+	fmt.Fprintf(&b, "<p>Elapsed: %s &middot; Total bytes: %d</p>\n", elapsed, totalBytes)
+
+	b.WriteString("<h2>Summary</h2>\n<ul>\n")
+	for _, action := range []string{reportActionFetched, reportActionDeduplicated, reportActionSkipped, reportActionRetried, reportActionFailed} {
+		fmt.Fprintf(&b, "<li>%s: %d</li>\n", action, counts[action])
+	}
+	b.WriteString("</ul>\n")
+
+	if len(failures) > 0 {
+		b.WriteString("<h2>Failures</h2>\n<ul>\n")
+		for _, e := range failures {
+			fmt.Fprintf(&b, "<li class=\"failure\">%s &mdash; %s</li>\n", html.EscapeString(e.URL), html.EscapeString(e.Error))
+		}
+		b.WriteString("</ul>\n")
+	}
+
+	if len(thumbnails) > 0 {
+		b.WriteString("<h2>Images</h2>\n<div>\n")
+		for _, e := range thumbnails {
+			fmt.Fprintf(&b, "<img src=\"%s\" title=\"%s\">\n", html.EscapeString(e.LocalPath), html.EscapeString(e.URL))
+		}
+		b.WriteString("</div>\n")
+	}
+
+	b.WriteString("</body></html>\n")
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
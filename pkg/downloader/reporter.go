@@ -0,0 +1,17 @@
+package downloader
+
+// ProgressReporter receives progress updates from ProcessRecords so a front
+// end (GUI or CLI) can render them however suits it, without ProcessRecords
+// depending on either one.
+type ProgressReporter interface {
+	// SetOverall reports fractional completion (0..1) of the whole batch.
+	SetOverall(fraction float64)
+	// SetWorker reports worker index's current transfer: the file being
+	// downloaded, bytes transferred/total (total is 0 if unknown), and the
+	// transfer rate in bytes per second.
+	SetWorker(index int, filename string, transferred, total int64, bytesPerSecond float64)
+	// WorkerIdle reports that worker index has no active transfer.
+	WorkerIdle(index int)
+	// Status reports a short human-readable status line for the whole run.
+	Status(message string)
+}
@@ -0,0 +1,109 @@
+package downloader
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how downloadAndSaveImage retries a transient
+// download failure: up to MaxRetries attempts total, backing off
+// exponentially from BaseDelay up to MaxDelay between tries.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy matches the app's original fixed retry behavior.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// retryableError wraps an error from a failed download attempt that is
+// worth retrying, optionally carrying a server-requested delay (from a
+// 429's Retry-After header) to honor before the next attempt instead of
+// the computed backoff.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// isTransientStatus reports whether an HTTP status code is worth retrying:
+// 429 (rate limited) or any 5xx server error.
+func isTransientStatus(status int) bool {
+	return status == http.StatusTooManyRequests || (status >= 500 && status < 600)
+}
+
+// parseRetryAfter parses a Retry-After header expressed as a number of
+// seconds. The HTTP-date form is not supported and is treated as absent.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// withRetry calls attempt up to policy.MaxRetries times, backing off
+// exponentially with jitter between tries. attempt should return a
+// *retryableError for failures worth retrying; any other error is returned
+// immediately without further attempts. onRetry, if non-nil, is invoked
+// with the failing error just before each retry (not after the final
+// attempt), so callers can log or report the retry. A MaxRetries below 1
+// is treated as 1 attempt rather than skipping the call outright.
+func withRetry(policy RetryPolicy, attempt func() error, onRetry func(err error)) error {
+	maxRetries := policy.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+
+	var lastErr error
+	for i := 0; i < maxRetries; i++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		var re *retryableError
+		if !errors.As(err, &re) {
+			return err
+		}
+		if i == maxRetries-1 {
+			break
+		}
+
+		if onRetry != nil {
+			onRetry(err)
+		}
+
+		delay := re.retryAfter
+		if delay == 0 {
+			delay = backoffDelay(policy, i)
+		}
+		time.Sleep(delay)
+	}
+	return lastErr
+}
+
+// backoffDelay returns an exponential backoff duration for retry attempt n
+// (0-based) under policy, with up to 50% jitter, capped at policy.MaxDelay.
+func backoffDelay(policy RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay * time.Duration(int64(1)<<uint(attempt))
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+	return delay - jitter/2
+}
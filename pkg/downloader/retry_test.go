@@ -0,0 +1,95 @@
+package downloader
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func fastPolicy(maxRetries int) RetryPolicy {
+	return RetryPolicy{MaxRetries: maxRetries, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+}
+
+func TestWithRetrySucceedsWithoutRetrying(t *testing.T) {
+	calls := 0
+	err := withRetry(fastPolicy(5), func() error {
+		calls++
+		return nil
+	}, func(error) { t.Fatal("onRetry should not be called on first-try success") })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestWithRetryRetriesRetryableErrors(t *testing.T) {
+	calls := 0
+	var retries int
+	err := withRetry(fastPolicy(3), func() error {
+		calls++
+		if calls < 3 {
+			return &retryableError{err: errors.New("transient")}
+		}
+		return nil
+	}, func(error) { retries++ })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+	if retries != 2 {
+		t.Fatalf("expected 2 onRetry calls, got %d", retries)
+	}
+}
+
+func TestWithRetryStopsAfterMaxRetries(t *testing.T) {
+	calls := 0
+	wantErr := &retryableError{err: errors.New("still failing")}
+	err := withRetry(fastPolicy(3), func() error {
+		calls++
+		return wantErr
+	}, nil)
+	if !errors.Is(err, wantErr) && err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected exactly 3 attempts, got %d", calls)
+	}
+}
+
+func TestWithRetryDoesNotRetryNonRetryableErrors(t *testing.T) {
+	calls := 0
+	wantErr := errors.New("permanent")
+	err := withRetry(fastPolicy(5), func() error {
+		calls++
+		return wantErr
+	}, func(error) { t.Fatal("onRetry should not be called for a non-retryable error") })
+	if err != wantErr {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 attempt, got %d", calls)
+	}
+}
+
+// A MaxRetries below 1 must still make one attempt rather than returning a
+// nil error without ever calling attempt.
+func TestWithRetryTreatsMaxRetriesBelowOneAsOneAttempt(t *testing.T) {
+	for _, maxRetries := range []int{0, -1} {
+		calls := 0
+		wantErr := &retryableError{err: errors.New("fails")}
+		err := withRetry(fastPolicy(maxRetries), func() error {
+			calls++
+			return wantErr
+		}, nil)
+		if calls != 1 {
+			t.Errorf("MaxRetries=%d: expected exactly 1 attempt, got %d", maxRetries, calls)
+		}
+		if err == nil {
+			t.Errorf("MaxRetries=%d: expected the attempt's error to be returned, got nil", maxRetries)
+		}
+	}
+}